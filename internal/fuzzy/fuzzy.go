@@ -0,0 +1,120 @@
+// Package fuzzy implements a small LSP-style fuzzy matcher for ranking
+// plugin names (or any other short strings) against a user query. It
+// has no dependency on any particular front-end so both the gui and
+// web packages can share it.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Match is one candidate scored against a query.
+type Match struct {
+	Candidate string
+	Score     int
+	// Positions holds the indices into Candidate that matched the
+	// query, in order, so a front-end can highlight them.
+	Positions []int
+}
+
+const (
+	scoreMatch         = 16
+	scoreContiguousRun = 8
+	scoreWordBoundary  = 12
+	scoreCamelBoundary = 10
+	gapPenalty         = 3
+	leadingGapPenalty  = 1
+)
+
+// Rank scores every candidate against query and returns the matches
+// that contain all of query's characters (in order, case-insensitively),
+// sorted by descending score. Candidates that don't contain query as a
+// subsequence are dropped.
+func Rank(query string, candidates []string) []Match {
+	if query == "" {
+		matches := make([]Match, len(candidates))
+		for i, c := range candidates {
+			matches[i] = Match{Candidate: c}
+		}
+		return matches
+	}
+	q := strings.ToLower(query)
+	matches := make([]Match, 0, len(candidates))
+	for _, c := range candidates {
+		if score, positions, ok := score(q, c); ok {
+			matches = append(matches, Match{Candidate: c, Score: score, Positions: positions})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// score scores the leftmost subsequence match of query in candidate:
+// each query character is matched to its first occurrence after the
+// previous match. With Smith-Waterman-style bonuses it rewards
+// contiguous runs and matches that land on word/camelCase boundaries,
+// and penalises gaps between matched characters. Other placements of
+// query in candidate, which could score higher, are not tried.
+func score(query, candidate string) (int, []int, bool) {
+	lower := strings.ToLower(candidate)
+	positions := make([]int, 0, len(query))
+	total := 0
+	lastMatch := -1
+	run := 0
+	qi := 0
+	for ci := 0; ci < len(lower) && qi < len(query); ci++ {
+		if lower[ci] != query[qi] {
+			continue
+		}
+		s := scoreMatch
+		if lastMatch >= 0 {
+			gap := ci - lastMatch - 1
+			if gap == 0 {
+				run++
+				s += run * scoreContiguousRun
+			} else {
+				run = 0
+				s -= gap * gapPenalty
+			}
+		} else if ci > 0 {
+			// Reward an early match less harshly than a deep one.
+			s -= ci * leadingGapPenalty
+		}
+		if ci == 0 || isWordBoundary(candidate, ci) {
+			s += scoreWordBoundary
+		} else if isCamelBoundary(candidate, ci) {
+			s += scoreCamelBoundary
+		}
+		total += s
+		positions = append(positions, ci)
+		lastMatch = ci
+		qi++
+	}
+	if qi != len(query) {
+		return 0, nil, false
+	}
+	return total, positions, true
+}
+
+func isWordBoundary(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	switch s[i-1] {
+	case '-', '_', '.', ' ', '/':
+		return true
+	}
+	return false
+}
+
+func isCamelBoundary(s string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	c := s[i]
+	prev := s[i-1]
+	return c >= 'A' && c <= 'Z' && !(prev >= 'A' && prev <= 'Z')
+}
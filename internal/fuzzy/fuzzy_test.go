@@ -0,0 +1,38 @@
+package fuzzy
+
+import "testing"
+
+func TestRankFiltersNonSubsequenceMatches(t *testing.T) {
+	candidates := []string{"base64", "base32", "gunzip", "sha256"}
+	matches := Rank("b64", candidates)
+	if len(matches) != 1 || matches[0].Candidate != "base64" {
+		t.Fatalf("expected only base64 to match, got %+v", matches)
+	}
+}
+
+func TestRankOrdersContiguousMatchesHigher(t *testing.T) {
+	// "sha2" is a contiguous run in sha256 but scattered in sha-gzip2.
+	candidates := []string{"sha-gzip2", "sha256"}
+	matches := Rank("sha2", candidates)
+	if len(matches) != 2 {
+		t.Fatalf("expected both candidates to match, got %+v", matches)
+	}
+	if matches[0].Candidate != "sha256" {
+		t.Fatalf("expected sha256 (contiguous match) to rank first, got %+v", matches)
+	}
+}
+
+func TestRankEmptyQueryReturnsAllUnscored(t *testing.T) {
+	candidates := []string{"base64", "base32"}
+	matches := Rank("", candidates)
+	if len(matches) != len(candidates) {
+		t.Fatalf("expected all candidates back for an empty query, got %+v", matches)
+	}
+}
+
+func TestRankIsCaseInsensitive(t *testing.T) {
+	matches := Rank("B64", []string{"base64"})
+	if len(matches) != 1 {
+		t.Fatalf("expected a case-insensitive match, got %+v", matches)
+	}
+}
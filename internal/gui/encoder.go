@@ -0,0 +1,338 @@
+package gui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"fyne.io/fyne"
+	"fyne.io/fyne/dialog"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/widget"
+	"github.com/takeshixx/deen/internal/plugins/external"
+	"github.com/takeshixx/deen/pkg/config"
+	"github.com/takeshixx/deen/pkg/types"
+)
+
+// defaultPreviewBytes is the fallback used when config.Config can't be
+// loaded; it matches config.Config.MaxPreviewBytes's own default.
+const defaultPreviewBytes = 64 * 1024
+
+// previewLimit is how much of an encoder's output is rendered into its
+// text area, read from config.Config.MaxPreviewBytes (key
+// "gui.max_preview_bytes", env DEEN_MAX_PREVIEW_BYTES). The rest of
+// the stream stays on disk and is only touched again if the user
+// saves it.
+func previewLimit() int64 {
+	cfg, err := config.Load()
+	if err != nil || cfg.MaxPreviewBytes <= 0 {
+		return defaultPreviewBytes
+	}
+	return int64(cfg.MaxPreviewBytes)
+}
+
+// DeenEncoder represents a single step of the processing chain. Its
+// input and output are always backed by a temp file rather than an
+// in-memory []byte/string, so chains over large blobs don't have to
+// fit in RAM.
+type DeenEncoder struct {
+	Parent     *DeenGUI
+	Plugin     *types.DeenPlugin
+	Unprocess  bool
+	Flags      *flag.FlagSet // current plugin's options, e.g. SHAKE's -len
+	Input      io.ReadSeeker
+	Output     io.ReadSeeker
+	OutputSize int64
+	inputFile  *os.File
+	outputFile *os.File
+	preview    *widget.Entry
+}
+
+// Close releases the encoder's backing temp files: it closes and
+// unlinks both inputFile and outputFile, if present. Callers must
+// call this when an encoder is dropped or replaced (e.g. a recipe
+// load rebuilding the chain) or when the GUI exits, otherwise every
+// edit leaks an fd plus an unlinked-but-open temp file.
+func (e *DeenEncoder) Close() error {
+	var err error
+	if e.inputFile != nil {
+		if cerr := e.inputFile.Close(); cerr != nil {
+			err = cerr
+		}
+		os.Remove(e.inputFile.Name())
+		e.inputFile = nil
+	}
+	if e.outputFile != nil {
+		if cerr := e.outputFile.Close(); cerr != nil {
+			err = cerr
+		}
+		os.Remove(e.outputFile.Name())
+		e.outputFile = nil
+	}
+	return err
+}
+
+// NewDeenEncoder creates an empty encoder backed by a temp file.
+func NewDeenEncoder(parent *DeenGUI) (e *DeenEncoder, err error) {
+	f, err := ioutil.TempFile("", "deen-encoder-")
+	if err != nil {
+		return
+	}
+	e = &DeenEncoder{
+		Parent:    parent,
+		inputFile: f,
+		Input:     f,
+	}
+	return
+}
+
+// SetContent replaces the encoder's input with data read from r,
+// spooling it to the encoder's temp file instead of buffering it.
+func (e *DeenEncoder) SetContent(r io.Reader) (err error) {
+	if e.inputFile == nil {
+		if e.inputFile, err = ioutil.TempFile("", "deen-encoder-"); err != nil {
+			return
+		}
+	}
+	if _, err = e.inputFile.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+	if err = e.inputFile.Truncate(0); err != nil {
+		return
+	}
+	if _, err = io.Copy(e.inputFile, r); err != nil {
+		return
+	}
+	_, err = e.inputFile.Seek(0, io.SeekStart)
+	e.Input = e.inputFile
+	return
+}
+
+// processTo runs the encoder's plugin over Input and writes the
+// processed bytes to w. A plugin with a ProcessDeenTaskFunc writes to
+// w as it produces output; for any other plugin the stream func
+// returns the whole result as one []byte, which is then written to w.
+func (e *DeenEncoder) processTo(w io.Writer) (err error) {
+	if e.Plugin == nil || e.Input == nil {
+		return
+	}
+	if _, err = e.Input.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+	if e.Plugin.ProcessDeenTaskFunc != nil {
+		task := types.NewDeenTask(w)
+		task.Reader = e.Input
+		if e.Unprocess {
+			e.Plugin.UnprocessDeenTaskFunc(task)
+		} else {
+			e.Plugin.ProcessDeenTaskFunc(task)
+		}
+		select {
+		case err = <-task.ErrChan:
+		case <-task.DoneChan:
+		}
+		return
+	}
+	var processed []byte
+	if e.Flags != nil && e.Plugin.ProcessStreamWithCliFlagsFunc != nil && !e.Unprocess {
+		processed, err = e.Plugin.ProcessStreamWithCliFlagsFunc(e.Flags, e.Input)
+	} else if e.Unprocess {
+		processed, err = e.Plugin.UnprocessStreamFunc(e.Input)
+	} else {
+		processed, err = e.Plugin.ProcessStreamFunc(e.Input)
+	}
+	if err != nil {
+		return
+	}
+	_, err = w.Write(processed)
+	return
+}
+
+// readPreview reads at most previewLimit() bytes from the start of r and
+// reports how many bytes the underlying stream actually holds.
+func readPreview(r io.ReadSeeker) (data []byte, total int64, err error) {
+	if r == nil {
+		return
+	}
+	if total, err = r.Seek(0, io.SeekEnd); err != nil {
+		return
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+	n := total
+	if limit := previewLimit(); n > limit {
+		n = limit
+	}
+	data = make([]byte, n)
+	_, err = io.ReadFull(r, data)
+	return
+}
+
+// readAll reads the entirety of r from the start, unlike readPreview
+// which clamps to previewLimit() for on-screen display. Callers that
+// need the real bytes behind an encoder (e.g. embedding a recipe's
+// input) must use this instead, or they'll silently save a truncated
+// copy for anything larger than the preview window.
+func readAll(r io.ReadSeeker) (data []byte, err error) {
+	if r == nil {
+		return
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+	data, err = ioutil.ReadAll(r)
+	if _, serr := r.Seek(0, io.SeekStart); err == nil {
+		err = serr
+	}
+	return
+}
+
+// createLayout builds the fyne widgets for this encoder. Only a
+// windowed preview of the output is ever rendered; the full stream
+// stays on disk until the user explicitly saves it.
+func (e *DeenEncoder) createLayout() fyne.CanvasObject {
+	e.preview = widget.NewMultiLineEntry()
+	data, total, err := readPreview(e.Output)
+	if err != nil {
+		dialog.ShowError(err, e.Parent.MainWindow)
+	}
+	e.preview.SetText(string(data))
+	status := widget.NewLabel(fmt.Sprintf("showing %d of %d bytes", len(data), total))
+	saveButton := widget.NewButton("Save output", func() {
+		e.showSaveDialog()
+	})
+	box := fyne.NewContainerWithLayout(layout.NewVBoxLayout(), e.createOptionsBar(), e.preview, status, saveButton)
+	return box
+}
+
+// createOptionsBar renders a small input next to the plugin selection
+// for each flag in e.Flags (e.g. SHAKE's "-len"), so GUI users can
+// tweak them without the CLI. e.Flags is set only when applyRecipeSteps
+// rebuilds the encoder from a recipe step that carries options; for
+// any other encoder it is nil and the bar is empty.
+func (e *DeenEncoder) createOptionsBar() fyne.CanvasObject {
+	bar := widget.NewHBox()
+	if e.Flags == nil {
+		return bar
+	}
+	e.Flags.VisitAll(func(f *flag.Flag) {
+		name, value := f.Name, f.Value
+		entry := widget.NewEntry()
+		entry.SetText(value.String())
+		entry.OnChanged = func(s string) {
+			if err := e.Flags.Set(name, s); err != nil {
+				dialog.ShowError(err, e.Parent.MainWindow)
+				return
+			}
+			e.Parent.updateGUI()
+		}
+		bar.Append(widget.NewLabel(name))
+		bar.Append(entry)
+	})
+	return bar
+}
+
+// showSaveDialog lets the user persist the encoder's full output
+// stream to disk without ever materialising it in the preview widget.
+func (e *DeenEncoder) showSaveDialog() {
+	if e.Output == nil {
+		return
+	}
+	d := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, e.Parent.MainWindow)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err = e.Output.Seek(0, io.SeekStart); err != nil {
+			dialog.ShowError(err, e.Parent.MainWindow)
+			return
+		}
+		if _, err = io.Copy(writer, e.Output); err != nil {
+			dialog.ShowError(err, e.Parent.MainWindow)
+		}
+	}, e.Parent.MainWindow)
+	d.Show()
+}
+
+// Close releases every encoder's backing temp files and stops any
+// persistent external-plugin child processes. Wired up as the main
+// window's OnClosed handler so a GUI exit doesn't leak them.
+func (dg *DeenGUI) Close() {
+	for _, e := range dg.Encoders {
+		e.Close()
+	}
+	external.CloseAll()
+}
+
+// processChain runs every encoder's plugin in order, one stage after
+// the other. Each stage's output goes through an io.Pipe into a new
+// temp file, and that temp file becomes the next encoder's input, so
+// the chain keeps every stage's output on disk rather than in memory.
+func (dg *DeenGUI) processChain() {
+	var input io.ReadSeeker
+	for _, e := range dg.Encoders {
+		if e.Plugin == nil {
+			break
+		}
+		if input != nil {
+			// This encoder's own inputFile (allocated up front by
+			// NewDeenEncoder) is only ever used for the root encoder;
+			// every other stage reads the previous stage's output
+			// instead, so close and unlink the unused one now rather
+			// than leaking it for the life of the chain.
+			if e.inputFile != nil {
+				e.inputFile.Close()
+				os.Remove(e.inputFile.Name())
+				e.inputFile = nil
+			}
+			e.Input = input
+		}
+		pr, pw := io.Pipe()
+		done := make(chan error, 1)
+		go func(e *DeenEncoder, pw *io.PipeWriter) {
+			done <- e.processTo(pw)
+			pw.Close()
+		}(e, pw)
+
+		out, err := ioutil.TempFile("", "deen-encoder-out-")
+		if err != nil {
+			dialog.ShowError(err, dg.MainWindow)
+			return
+		}
+		if _, err = io.Copy(out, pr); err != nil {
+			out.Close()
+			os.Remove(out.Name())
+			dialog.ShowError(err, dg.MainWindow)
+			return
+		}
+		if err = <-done; err != nil {
+			out.Close()
+			os.Remove(out.Name())
+			dialog.ShowError(err, dg.MainWindow)
+			return
+		}
+		if _, err = out.Seek(0, io.SeekStart); err != nil {
+			out.Close()
+			os.Remove(out.Name())
+			dialog.ShowError(err, dg.MainWindow)
+			return
+		}
+		if e.outputFile != nil {
+			e.outputFile.Close()
+			os.Remove(e.outputFile.Name())
+		}
+		e.outputFile = out
+		if info, statErr := out.Stat(); statErr == nil {
+			e.OutputSize = info.Size()
+		}
+		e.Output = out
+		input = out
+	}
+}
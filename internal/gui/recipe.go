@@ -0,0 +1,142 @@
+package gui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+
+	"fyne.io/fyne"
+	"fyne.io/fyne/dialog"
+	"github.com/takeshixx/deen/internal/plugins"
+	"github.com/takeshixx/deen/pkg/recipe"
+)
+
+// toRecipeSteps converts the current encoder chain into the
+// serializable pkg/recipe form. This conversion lives here, not in
+// pkg/recipe, so that package never has to import internal/gui.
+func (dg *DeenGUI) toRecipeSteps() []recipe.Step {
+	steps := make([]recipe.Step, 0, len(dg.Encoders))
+	for _, e := range dg.Encoders {
+		if e.Plugin == nil {
+			continue
+		}
+		steps = append(steps, recipe.Step{
+			Plugin:    e.Plugin.Name,
+			Unprocess: e.Unprocess,
+			Options:   recipe.SnapshotFlags(e.Flags),
+		})
+	}
+	return steps
+}
+
+// applyRecipeSteps rebuilds the encoder chain from a loaded recipe. A
+// recipe with zero steps is a valid, if unusual, document — the root
+// encoder must always exist regardless, so one empty encoder is kept
+// in that case instead of leaving dg.Encoders empty.
+func (dg *DeenGUI) applyRecipeSteps(steps []recipe.Step) error {
+	encoders := make([]*DeenEncoder, 0, len(steps))
+	for _, step := range steps {
+		p, err := plugins.GetByName(step.Plugin)
+		if err != nil {
+			return err
+		}
+		e, err := NewDeenEncoder(dg)
+		if err != nil {
+			return err
+		}
+		e.Plugin = p
+		e.Unprocess = step.Unprocess
+		if len(step.Options) > 0 && p.AddCliOptionsFunc != nil {
+			e.Flags = p.AddCliOptionsFunc(p, nil)
+			if err := recipe.ApplyFlags(e.Flags, step.Options); err != nil {
+				return err
+			}
+		}
+		encoders = append(encoders, e)
+	}
+	if len(encoders) == 0 {
+		root, err := NewDeenEncoder(dg)
+		if err != nil {
+			return err
+		}
+		encoders = append(encoders, root)
+	}
+	for _, old := range dg.Encoders {
+		old.Close()
+	}
+	dg.Encoders = encoders
+	return nil
+}
+
+// showSaveRecipeDialog writes the current chain out as a recipe
+// document the user can reload later with Load Recipe.
+func (dg *DeenGUI) showSaveRecipeDialog() {
+	var input []byte
+	if len(dg.Encoders) > 0 {
+		if data, err := readAll(dg.Encoders[0].Input); err == nil {
+			input = data
+		}
+	}
+	data, err := recipe.Marshal(dg.toRecipeSteps(), input)
+	if err != nil {
+		dialog.ShowError(err, dg.MainWindow)
+		return
+	}
+	d := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, dg.MainWindow)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err = writer.Write(data); err != nil {
+			dialog.ShowError(err, dg.MainWindow)
+		}
+	}, dg.MainWindow)
+	d.Show()
+}
+
+// showLoadRecipeDialog reads a recipe document and rebuilds the
+// encoder chain from it.
+func (dg *DeenGUI) showLoadRecipeDialog() {
+	d := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, dg.MainWindow)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+		data, err := ioutil.ReadAll(io.LimitReader(reader, 1<<20))
+		if err != nil {
+			dialog.ShowError(err, dg.MainWindow)
+			return
+		}
+		r, err := recipe.Unmarshal(data)
+		if err != nil {
+			dialog.ShowError(err, dg.MainWindow)
+			return
+		}
+		if err = dg.applyRecipeSteps(r.Steps); err != nil {
+			dialog.ShowError(err, dg.MainWindow)
+			return
+		}
+		if r.Input != "" {
+			input, err := base64.StdEncoding.DecodeString(r.Input)
+			if err != nil {
+				dialog.ShowError(err, dg.MainWindow)
+				return
+			}
+			if err = dg.Encoders[0].SetContent(bytes.NewReader(input)); err != nil {
+				dialog.ShowError(err, dg.MainWindow)
+				return
+			}
+		}
+		dg.updateGUI()
+	}, dg.MainWindow)
+	d.Show()
+}
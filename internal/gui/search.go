@@ -0,0 +1,183 @@
+package gui
+
+import (
+	"bytes"
+	"fmt"
+
+	"fyne.io/fyne"
+	"fyne.io/fyne/driver/desktop"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/widget"
+	"github.com/takeshixx/deen/internal/fuzzy"
+	"github.com/takeshixx/deen/internal/plugins"
+	"github.com/takeshixx/deen/pkg/types"
+)
+
+// pluginSearchResults is how many fuzzy matches are shown at once in
+// the popup, same order of magnitude as a typical LSP suggestion box.
+const pluginSearchResults = 8
+
+// pluginSearch is the floating suggestion box bound to F2. It re-ranks
+// plugins.PluginCategories on every keystroke via internal/fuzzy and
+// lets the user run or insert the focused match.
+type pluginSearch struct {
+	dg        *DeenGUI
+	popup     *widget.PopUp
+	input     *widget.Entry
+	list      *widget.Box
+	detail    *widget.Label
+	doc       *widget.Label
+	matches   []fuzzy.Match
+	selected  int
+	shortcuts []fyne.Shortcut // bound while the popup is open, removed on close
+}
+
+func (dg *DeenGUI) showPluginSearch() {
+	ps := &pluginSearch{dg: dg}
+	ps.input = widget.NewEntry()
+	ps.input.OnChanged = ps.onQueryChanged
+	ps.list = widget.NewVBox()
+	ps.detail = widget.NewLabel("")
+	ps.doc = widget.NewLabel("")
+	content := fyne.NewContainerWithLayout(
+		layout.NewVBoxLayout(),
+		ps.input,
+		ps.list,
+		ps.detail,
+		ps.doc,
+	)
+	ps.popup = widget.NewModalPopUp(content, dg.MainWindow.Canvas())
+	ps.bindNavigationShortcuts()
+	ps.onQueryChanged("")
+}
+
+// bindNavigationShortcuts wires the arrow keys, Enter, Tab and Escape
+// to moving/running/inserting/closing the popup, editor-style. These
+// are scoped to the popup being open: closePopup removes every
+// shortcut it adds here so Enter/arrows/Tab go back to their normal
+// jobs (newline, cursor movement, focus traversal) once it's gone.
+func (ps *pluginSearch) bindNavigationShortcuts() {
+	canvas := ps.dg.MainWindow.Canvas()
+	bind := func(key fyne.KeyName, fn func()) {
+		shortcut := &desktop.CustomShortcut{KeyName: key}
+		canvas.AddShortcut(shortcut, func(fyne.Shortcut) { fn() })
+		ps.shortcuts = append(ps.shortcuts, shortcut)
+	}
+	bind(fyne.KeyUp, func() { ps.moveSelection(-1) })
+	bind(fyne.KeyDown, func() { ps.moveSelection(1) })
+	bind(fyne.KeyReturn, ps.run)
+	bind(fyne.KeyEscape, ps.closePopup)
+	bind(fyne.KeyTab, func() {
+		if ps.dg.ChainRecipeInput != nil {
+			ps.insert(ps.dg.ChainRecipeInput)
+		}
+	})
+}
+
+// closePopup hides the popup and unbinds every shortcut
+// bindNavigationShortcuts registered on the main canvas.
+func (ps *pluginSearch) closePopup() {
+	canvas := ps.dg.MainWindow.Canvas()
+	for _, shortcut := range ps.shortcuts {
+		canvas.RemoveShortcut(shortcut)
+	}
+	ps.shortcuts = nil
+	ps.popup.Hide()
+}
+
+// onQueryChanged re-ranks the plugin list against the current query
+// and re-renders the suggestion list; it always leaves the top match
+// focused.
+func (ps *pluginSearch) onQueryChanged(query string) {
+	ps.matches = fuzzy.Rank(query, ps.dg.Plugins)
+	if len(ps.matches) > pluginSearchResults {
+		ps.matches = ps.matches[:pluginSearchResults]
+	}
+	ps.selected = 0
+	ps.list.Children = nil
+	for i, m := range ps.matches {
+		idx := i
+		ps.list.Append(widget.NewButton(m.Candidate, func() {
+			ps.selected = idx
+			ps.run()
+		}))
+	}
+	ps.list.Refresh()
+	ps.updateDetail()
+}
+
+// moveSelection moves the focused suggestion by delta, wrapping at
+// the ends, and refreshes the detail/documentation panes. Bound to
+// the arrow keys.
+func (ps *pluginSearch) moveSelection(delta int) {
+	if len(ps.matches) == 0 {
+		return
+	}
+	ps.selected = (ps.selected + delta + len(ps.matches)) % len(ps.matches)
+	ps.updateDetail()
+}
+
+// updateDetail fills in the category/aliases "detail" line and the
+// plugin's CLI Usage text as the "documentation" panel for whichever
+// match currently has focus.
+func (ps *pluginSearch) updateDetail() {
+	if len(ps.matches) == 0 {
+		ps.detail.SetText("")
+		ps.doc.SetText("")
+		return
+	}
+	name := ps.matches[ps.selected].Candidate
+	p, err := plugins.GetByName(name)
+	if err != nil {
+		ps.detail.SetText("")
+		ps.doc.SetText("")
+		return
+	}
+	ps.detail.SetText(fmt.Sprintf("%s (aliases: %v)", p.Type, p.Aliases))
+	ps.doc.SetText(usageText(p))
+}
+
+// usageText renders a plugin's flag descriptions (name, default,
+// usage string) for the documentation panel. Plugins across this repo
+// hardcode their Usage closures to write straight to os.Stderr, so
+// capturing the real Usage() output would mean swapping the process's
+// stderr out from under every other goroutine for the duration of the
+// call — racy with anything else writing to stderr concurrently (e.g.
+// log output), and an outright deadlock risk if a plugin ever writes
+// more than the OS pipe buffer before the redirect is undone. Instead,
+// point the FlagSet's own output at a buffer and use PrintDefaults
+// directly, which every flag.FlagSet respects regardless of what its
+// Usage closure does.
+func usageText(p *types.DeenPlugin) string {
+	if p.AddCliOptionsFunc == nil {
+		return ""
+	}
+	flags := p.AddCliOptionsFunc(p, nil)
+	if flags == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+	flags.PrintDefaults()
+	return buf.String()
+}
+
+// run executes the currently focused match on the active encoder and
+// closes the popup.
+func (ps *pluginSearch) run() {
+	if len(ps.matches) == 0 {
+		return
+	}
+	ps.closePopup()
+	ps.dg.RunPlugin(ps.matches[ps.selected].Candidate)
+}
+
+// insert writes the focused match's name into a chain-recipe input
+// without running it, bound to Tab, then closes the popup.
+func (ps *pluginSearch) insert(recipeInput *widget.Entry) {
+	if len(ps.matches) == 0 {
+		return
+	}
+	recipeInput.SetText(recipeInput.Text + ps.matches[ps.selected].Candidate)
+	ps.closePopup()
+}
@@ -1,7 +1,6 @@
 package gui
 
 import (
-	"io/ioutil"
 	"log"
 
 	"fyne.io/fyne"
@@ -12,6 +11,8 @@ import (
 	"fyne.io/fyne/theme"
 	"fyne.io/fyne/widget"
 	"github.com/takeshixx/deen/internal/plugins"
+	"github.com/takeshixx/deen/internal/plugins/external"
+	"github.com/takeshixx/deen/pkg/config"
 )
 
 // DeenGUI represents a GUI instance.
@@ -27,6 +28,7 @@ type DeenGUI struct {
 	HistoryList          *widget.Group
 	History              []string
 	CurrentFocus         int // The index of the encoder widget in Encoders
+	ChainRecipeInput     *widget.Entry // Populated by Tab from the plugin search popup
 }
 
 // NewDeenGUI initializes a new DeenGUI instance.
@@ -43,6 +45,7 @@ func NewDeenGUI() (dg *DeenGUI, err error) {
 	dg.MainWindow = dg.App.NewWindow("deen")
 	dg.newMainLayout()
 	dg.newMainMenu()
+	loadExternalPlugins()
 	dg.loadPluginList()
 
 	// Create the root encoder widget (must always exist)
@@ -50,8 +53,14 @@ func NewDeenGUI() (dg *DeenGUI, err error) {
 		return
 	}
 
-	// Setup the theme
-	if dg.App.Preferences().String("theme") == "light" {
+	// Setup the theme. Routed through pkg/config instead of
+	// App.Preferences() directly so the same setting works headless
+	// (env var, config file) and not just inside a running GUI.
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	if cfg.GUITheme == "light" {
 		dg.App.Settings().SetTheme(theme.LightTheme())
 	} else {
 		dg.App.Settings().SetTheme(theme.DarkTheme())
@@ -60,6 +69,7 @@ func NewDeenGUI() (dg *DeenGUI, err error) {
 	dg.MainWindow.SetMaster()
 	dg.MainWindow.SetContent(dg.MainLayout)
 	dg.MainWindow.Resize(fyne.NewSize(640, 480))
+	dg.MainWindow.SetOnClosed(dg.Close)
 	dg.addCustomShortcuts()
 	dg.updateGUI()
 	return
@@ -98,16 +108,26 @@ func (dg *DeenGUI) newMainMenu() {
 					}, dg.MainWindow)
 					fd.Show()
 				}),
+				fyne.NewMenuItem("Save Recipe", func() {
+					dg.showSaveRecipeDialog()
+				}),
+				fyne.NewMenuItem("Load Recipe", func() {
+					dg.showLoadRecipeDialog()
+				}),
 				// A quit item will be appended to our first menu
 			),
 			fyne.NewMenu("Theme",
 				fyne.NewMenuItem("Light", func() {
 					dg.App.Settings().SetTheme(theme.LightTheme())
-					dg.App.Preferences().SetString("theme", "light")
+					if err := config.Set("gui.theme", "light"); err != nil {
+						dialog.ShowError(err, dg.MainWindow)
+					}
 				}),
 				fyne.NewMenuItem("Dark", func() {
 					dg.App.Settings().SetTheme(theme.DarkTheme())
-					dg.App.Preferences().SetString("theme", "dark")
+					if err := config.Set("gui.theme", "dark"); err != nil {
+						dialog.ShowError(err, dg.MainWindow)
+					}
 				}),
 			),
 			fyne.NewMenu("Help",
@@ -117,6 +137,33 @@ func (dg *DeenGUI) newMainMenu() {
 			)))
 }
 
+// loadExternalPlugins discovers user-supplied plugin executables and
+// registers them so loadPluginList's accordion picks them up exactly
+// like a compiled-in plugin. The directory is config.Config.PluginDir
+// (key "plugins.dir", env DEEN_PLUGIN_DIR) when set, falling back to
+// external.DefaultDir() otherwise.
+func loadExternalPlugins() {
+	dir := ""
+	if cfg, err := config.Load(); err == nil {
+		dir = cfg.PluginDir
+	}
+	if dir == "" {
+		var err error
+		if dir, err = external.DefaultDir(); err != nil {
+			log.Println("[WARN] external plugins:", err)
+			return
+		}
+	}
+	found, err := external.Discover(dir)
+	if err != nil {
+		log.Println("[WARN] external plugins:", err)
+		return
+	}
+	for _, p := range found {
+		plugins.Register(p)
+	}
+}
+
 // Populate the DeenGUI.PluginList field
 func (dg *DeenGUI) loadPluginList() {
 	dg.Plugins = []string{}
@@ -198,9 +245,10 @@ func (dg *DeenGUI) updateEncoderWidgets() {
 }
 
 func (dg *DeenGUI) fileOpened(f fyne.URIReadCloser) {
-	input, err := ioutil.ReadAll(f)
-	if err != nil {
+	defer f.Close()
+	// Stream straight into the encoder's backing temp file instead of
+	// reading the whole file into memory first.
+	if err := dg.Encoders[0].SetContent(f); err != nil {
 		dialog.ShowError(err, dg.MainWindow)
 	}
-	dg.Encoders[0].SetContent(input)
 }
@@ -0,0 +1,89 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/takeshixx/deen/pkg/types"
+)
+
+// manifestTimeout bounds how long Discover waits on a single plugin's
+// --deen-manifest probe. Discover runs synchronously during GUI
+// startup, so one hung executable in the plugins directory must not
+// be able to block the app from ever showing its window.
+const manifestTimeout = 2 * time.Second
+
+// DefaultDir returns the directory external plugin executables are
+// discovered in: $XDG_DATA_HOME/deen/plugins, falling back to
+// ~/.local/share/deen/plugins if XDG_DATA_HOME isn't set.
+func DefaultDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "deen", "plugins"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "deen", "plugins"), nil
+}
+
+// Discover scans dir for executables, asks each for its manifest via
+// --deen-manifest, and wraps every successfully-described one as a
+// types.DeenPlugin. A single broken plugin is skipped, not fatal.
+func Discover(dir string) ([]*types.DeenPlugin, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var plugins []*types.DeenPlugin
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := entry.Info()
+		if err != nil || info.Mode()&0111 == 0 {
+			continue
+		}
+		m, err := fetchManifest(path)
+		if err != nil {
+			continue
+		}
+		plugins = append(plugins, newPlugin(path, m))
+	}
+	return plugins, nil
+}
+
+// fetchManifest invokes path once with --deen-manifest and decodes its
+// JSON descriptor from stdout, killing it if it doesn't answer within
+// manifestTimeout.
+func fetchManifest(path string) (Manifest, error) {
+	var m Manifest
+	ctx, cancel := context.WithTimeout(context.Background(), manifestTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, "--deen-manifest")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return m, fmt.Errorf("external: %s --deen-manifest: timed out after %s", path, manifestTimeout)
+		}
+		return m, fmt.Errorf("external: %s --deen-manifest: %w", path, err)
+	}
+	if err := json.Unmarshal(out.Bytes(), &m); err != nil {
+		return m, fmt.Errorf("external: %s: invalid manifest: %w", path, err)
+	}
+	if m.Name == "" {
+		return m, fmt.Errorf("external: %s: manifest missing name", path)
+	}
+	return m, nil
+}
@@ -0,0 +1,32 @@
+// Package external discovers and wraps out-of-process plugins: user
+// supplied executables that speak a tiny JSON manifest + stdin/stdout
+// protocol instead of being compiled into the deen binary.
+package external
+
+// Manifest is what a plugin executable prints to stdout when invoked
+// with --deen-manifest.
+type Manifest struct {
+	Name      string         `json:"name"`
+	Aliases   []string       `json:"aliases"`
+	Type      string         `json:"type"`
+	Unprocess bool           `json:"unprocess"`
+	Flags     []ManifestFlag `json:"flags"`
+	// Framing selects how repeated invocations talk to the child.
+	// "" (the default) execs the binary once per call. "length-prefixed"
+	// keeps one child running and frames each request/response so a
+	// busy chain doesn't pay exec overhead per step.
+	Framing string `json:"framing"`
+}
+
+// ManifestFlag describes one CLI flag the plugin wants exposed, e.g.
+// so the GUI can render an options bar for it like built-in plugins.
+type ManifestFlag struct {
+	Name    string `json:"name"`
+	Default string `json:"default"`
+	Usage   string `json:"usage"`
+}
+
+const (
+	framingExec           = ""
+	framingLengthPrefixed = "length-prefixed"
+)
@@ -0,0 +1,106 @@
+package external
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+)
+
+// persistentChild keeps one external plugin process running across
+// calls and frames each request as [direction byte][4-byte big-endian
+// args length][JSON-encoded []string args][4-byte big-endian payload
+// length][payload], and each response as [4-byte big-endian length]
+// [payload], so a busy chain doesn't pay exec overhead on every step.
+type persistentChild struct {
+	path  string
+	mu    sync.Mutex
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	stdout io.ReadCloser
+}
+
+func newPersistentChild(path string) *persistentChild {
+	return &persistentChild{path: path}
+}
+
+// ensureStarted lazily spawns the child the first time it's needed.
+func (c *persistentChild) ensureStarted() error {
+	if c.cmd != nil {
+		return nil
+	}
+	cmd := exec.Command(c.path, "--deen-serve")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	c.cmd, c.stdin, c.stdout = cmd, stdin, stdout
+	return nil
+}
+
+// process sends one length-prefixed request, including any per-call
+// CLI flags (rendered the same way the exec-per-call path passes them:
+// "--name", "value", ...), and waits for the matching response.
+func (c *persistentChild) process(data []byte, unprocess bool, extraArgs []string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.ensureStarted(); err != nil {
+		return nil, err
+	}
+	direction := byte(0)
+	if unprocess {
+		direction = 1
+	}
+	argsJSON, err := json.Marshal(extraArgs)
+	if err != nil {
+		return nil, fmt.Errorf("external: encoding request args: %w", err)
+	}
+	if _, err := c.stdin.Write([]byte{direction}); err != nil {
+		return nil, fmt.Errorf("external: writing request header: %w", err)
+	}
+	if err := binary.Write(c.stdin, binary.BigEndian, uint32(len(argsJSON))); err != nil {
+		return nil, fmt.Errorf("external: writing request args length: %w", err)
+	}
+	if _, err := c.stdin.Write(argsJSON); err != nil {
+		return nil, fmt.Errorf("external: writing request args: %w", err)
+	}
+	if err := binary.Write(c.stdin, binary.BigEndian, uint32(len(data))); err != nil {
+		return nil, fmt.Errorf("external: writing request length: %w", err)
+	}
+	if _, err := c.stdin.Write(data); err != nil {
+		return nil, fmt.Errorf("external: writing request body: %w", err)
+	}
+	var respLen uint32
+	if err := binary.Read(c.stdout, binary.BigEndian, &respLen); err != nil {
+		return nil, fmt.Errorf("external: reading response length: %w", err)
+	}
+	resp := make([]byte, respLen)
+	if _, err := io.ReadFull(c.stdout, resp); err != nil {
+		return nil, fmt.Errorf("external: reading response body: %w", err)
+	}
+	return resp, nil
+}
+
+// Close stops the child process, if one was ever started. Callers
+// must call this on shutdown, otherwise a persistent child outlives
+// the deen process that spawned it.
+func (c *persistentChild) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cmd == nil || c.cmd.Process == nil {
+		return nil
+	}
+	c.stdin.Close()
+	err := c.cmd.Process.Kill()
+	c.cmd.Wait()
+	return err
+}
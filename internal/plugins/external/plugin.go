@@ -0,0 +1,110 @@
+package external
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"sync"
+
+	"github.com/takeshixx/deen/pkg/types"
+)
+
+// children tracks every persistentChild started by newPlugin, so
+// CloseAll can tear them down on GUI exit instead of leaving them
+// running after the deen process itself has quit.
+var (
+	childrenMu sync.Mutex
+	children   []*persistentChild
+)
+
+// CloseAll stops every persistent external-plugin child process
+// started so far. Callers (e.g. the GUI's shutdown handler) must call
+// this on exit.
+func CloseAll() {
+	childrenMu.Lock()
+	defer childrenMu.Unlock()
+	for _, c := range children {
+		c.Close()
+	}
+	children = nil
+}
+
+// newPlugin synthesises a types.DeenPlugin whose Process/Unprocess
+// funcs run the discovered executable, matching the shape every
+// built-in plugin in this repo already exposes.
+func newPlugin(path string, m Manifest) *types.DeenPlugin {
+	p := &types.DeenPlugin{
+		Name:      m.Name,
+		Aliases:   m.Aliases,
+		Type:      m.Type,
+		Unprocess: false,
+	}
+	var child *persistentChild
+	if m.Framing == framingLengthPrefixed {
+		child = newPersistentChild(path)
+		childrenMu.Lock()
+		children = append(children, child)
+		childrenMu.Unlock()
+	}
+	p.ProcessStreamFunc = func(reader io.Reader) ([]byte, error) {
+		return invoke(path, child, reader, false)
+	}
+	if m.Unprocess {
+		p.UnprocessStreamFunc = func(reader io.Reader) ([]byte, error) {
+			return invoke(path, child, reader, true)
+		}
+	}
+	if len(m.Flags) > 0 {
+		p.AddCliOptionsFunc = func(self *types.DeenPlugin, args []string) *flag.FlagSet {
+			fs := flag.NewFlagSet(m.Name, flag.ExitOnError)
+			for _, f := range m.Flags {
+				fs.String(f.Name, f.Default, f.Usage)
+			}
+			fs.Usage = func() {
+				fmt.Printf("Usage of %s (external plugin):\n\n", m.Name)
+				fs.PrintDefaults()
+			}
+			fs.Parse(args)
+			return fs
+		}
+		p.ProcessStreamWithCliFlagsFunc = func(flags *flag.FlagSet, reader io.Reader) ([]byte, error) {
+			var extra []string
+			flags.VisitAll(func(f *flag.Flag) {
+				extra = append(extra, "--"+f.Name, f.Value.String())
+			})
+			return invoke(path, child, reader, false, extra...)
+		}
+	}
+	return p
+}
+
+// invoke runs one request against the child, either over the
+// length-prefixed framing of a long-running process, or by exec'ing
+// path fresh for this single call. The persistent-child path needs the
+// full payload up front to write its length-prefix header, but the
+// exec-per-call path streams reader straight into the child's stdin
+// instead of buffering it in memory first.
+func invoke(path string, child *persistentChild, reader io.Reader, unprocess bool, extraArgs ...string) ([]byte, error) {
+	if child != nil {
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		return child.process(data, unprocess, extraArgs)
+	}
+	args := extraArgs
+	if unprocess {
+		args = append(args, "--deen-unprocess")
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = reader
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("external: %s: %w", path, err)
+	}
+	return out.Bytes(), nil
+}
@@ -6,8 +6,8 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"strings"
 
+	"github.com/gopherjs/gopherjs/js"
 	"github.com/gopherjs/vecty"
 	"github.com/gopherjs/vecty/elem"
 	"github.com/gopherjs/vecty/event"
@@ -15,13 +15,18 @@ import (
 	"github.com/takeshixx/deen/pkg/types"
 )
 
+// previewBytes is how much of FullContent is rendered into the
+// textarea. The rest stays off-DOM and is only touched by Save output.
+const previewBytes = 64 * 1024
+
 type EncoderWidget struct {
 	vecty.Core
-	Parent     *DeenWeb
-	Content    string
-	ContentLen vecty.MarkupOrChild
-	Plugin     *types.DeenPlugin
-	Border     bool
+	Parent      *DeenWeb
+	FullContent []byte // the stage's full stream; never rendered directly
+	Content     string // windowed preview shown in the textarea
+	ContentLen  vecty.MarkupOrChild
+	Plugin      *types.DeenPlugin
+	Border      bool
 }
 
 func (e *EncoderWidget) Render() vecty.ComponentOrHTML {
@@ -44,9 +49,8 @@ func (e *EncoderWidget) Render() vecty.ComponentOrHTML {
 				vecty.Style("display", "inline-block"),
 				vecty.Property("rows", 20),
 				event.Input(func(event *vecty.Event) {
-					e.Content = event.Target.Get("value").String()
+					e.SetContent([]byte(event.Target.Get("value").String()))
 					e.Parent.RunChainFrom(e)
-					vecty.Rerender(e)
 				}),
 				event.Click(func(event *vecty.Event) {
 					e.Parent.SetCurrentEncoder(e)
@@ -54,16 +58,46 @@ func (e *EncoderWidget) Render() vecty.ComponentOrHTML {
 			),
 			vecty.Text(e.Content),
 		),
+		elem.Label(vecty.Text(fmt.Sprintf("showing %d of %d bytes", len(e.Content), len(e.FullContent)))),
+		elem.Button(
+			vecty.Markup(
+				event.Click(func(event *vecty.Event) {
+					e.SaveOutput()
+				}),
+			),
+			vecty.Text("Save output"),
+		),
 	)
 	return w
 }
 
-func (e *EncoderWidget) SetContent(data string) {
-	e.Content = data
+// SaveOutput triggers a browser download of the stage's full output
+// stream, so a user never has to pull the whole thing through the
+// preview textarea to get at it.
+func (e *EncoderWidget) SaveOutput() {
+	blob := js.Global.Get("Blob").New([]interface{}{e.FullContent}, map[string]interface{}{"type": "application/octet-stream"})
+	url := js.Global.Get("URL").Call("createObjectURL", blob)
+	link := js.Global.Get("document").Call("createElement", "a")
+	link.Set("href", url)
+	link.Set("download", "deen-output.bin")
+	link.Call("click")
+	js.Global.Get("URL").Call("revokeObjectURL", url)
+}
+
+// SetContent replaces the stage's full stream and refreshes the
+// windowed preview shown in the textarea.
+func (e *EncoderWidget) SetContent(data []byte) {
+	e.FullContent = data
+	shown := len(data)
+	if shown > previewBytes {
+		shown = previewBytes
+	}
+	e.Content = string(data[:shown])
 	e.Render()
 }
 
 func (e *EncoderWidget) ClearContent() {
+	e.FullContent = nil
 	e.Content = ""
 	e.Render()
 }
@@ -74,8 +108,8 @@ func (e *EncoderWidget) Process() (processed []byte, err error) {
 		return
 	}
 	var reader io.Reader
-	if len(e.Content) > 1 {
-		reader = strings.NewReader(e.Content)
+	if len(e.FullContent) > 0 {
+		reader = bytes.NewReader(e.FullContent)
 	}
 	if e.Plugin.ProcessDeenTaskFunc != nil {
 		var outWriter bytes.Buffer
@@ -144,7 +178,7 @@ func (e *EncoderWidget) CreatePluginSelects() vecty.ComponentOrHTML {
 
 func (e *EncoderWidget) CreateEncoderInfo() vecty.MarkupOrChild {
 	e.ContentLen = elem.Label(
-		vecty.Text(fmt.Sprintf("Len: %d", len(e.Content))),
+		vecty.Text(fmt.Sprintf("Len: %d", len(e.FullContent))),
 	)
 	return e.ContentLen
 }
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Info implements "deen config info": for every known setting it
+// reports the key, description, default, current value and the env
+// var that overrides it.
+func Info() (string, error) {
+	c, err := Load()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, f := range fields() {
+		current := reflect.ValueOf(*c).Field(f.index).Interface()
+		fmt.Fprintf(&b, "%s\n", f.Key)
+		fmt.Fprintf(&b, "  description: %s\n", f.Desc)
+		fmt.Fprintf(&b, "  default:     %s\n", f.Default)
+		fmt.Fprintf(&b, "  current:     %v\n", current)
+		fmt.Fprintf(&b, "  env:         %s\n", f.Env)
+	}
+	return b.String(), nil
+}
+
+// Set implements "deen config set <key> <value>": it writes value
+// under key in $XDG_CONFIG_HOME/deen/config.yaml, leaving every other
+// key untouched.
+func Set(key, value string) error {
+	f, ok := fieldByKey(key)
+	if !ok {
+		return fmt.Errorf("config: unknown key %q", key)
+	}
+	if err := setField(&Config{}, f.index, value); err != nil {
+		return fmt.Errorf("config: %q: %w", key, err)
+	}
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	raw, err := readRaw(path)
+	if os.IsNotExist(err) {
+		raw = map[string]string{}
+	} else if err != nil {
+		return err
+	}
+	raw[key] = value
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// fieldByKey looks up a field by its tagged key, used by Set to both
+// check the key is known and validate the value against its Go kind.
+func fieldByKey(key string) (field, bool) {
+	for _, f := range fields() {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return field{}, false
+}
@@ -0,0 +1,155 @@
+// Package config is deen's unified configuration subsystem. Settings
+// are declared once as tagged struct fields and merged from defaults,
+// a YAML config file, and environment variables, in that order, so
+// every front-end (gui, web, cli) reads the same values the same way.
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config holds every setting deen knows about. Add a field here, tag
+// it, and it's automatically picked up by Load, Info and Set.
+type Config struct {
+	GUITheme        string `key:"gui.theme" default:"dark" env:"DEEN_GUI_THEME" desc:"Fyne theme used by the desktop GUI (dark or light)"`
+	GUIFont         string `key:"gui.font" default:"" env:"DEEN_GUI_FONT" desc:"Path to a custom TTF font for the desktop GUI; stored only, the GUI does not load it"`
+	DefaultEncoding string `key:"output.default_encoding" default:"" env:"DEEN_DEFAULT_ENCODING" desc:"Name of a default output plugin; stored only, no front-end applies it"`
+	PluginDir       string `key:"plugins.dir" default:"" env:"DEEN_PLUGIN_DIR" desc:"Directory external plugin executables are discovered in"`
+	MaxPreviewBytes int    `key:"gui.max_preview_bytes" default:"65536" env:"DEEN_MAX_PREVIEW_BYTES" desc:"Bytes of processed output rendered into an encoder's preview"`
+}
+
+// field describes one Config struct field via its tags, used by both
+// Load and the "deen config info"/"deen config set" subcommands.
+type field struct {
+	Key     string
+	Default string
+	Env     string
+	Desc    string
+	index   int
+}
+
+// fields reflects over Config once and returns every tagged field.
+func fields() []field {
+	t := reflect.TypeOf(Config{})
+	out := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		out = append(out, field{
+			Key:     sf.Tag.Get("key"),
+			Default: sf.Tag.Get("default"),
+			Env:     sf.Tag.Get("env"),
+			Desc:    sf.Tag.Get("desc"),
+			index:   i,
+		})
+	}
+	return out
+}
+
+// Dir returns $XDG_CONFIG_HOME/deen, falling back to ~/.config/deen.
+func Dir() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "deen"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "deen"), nil
+}
+
+// Path returns the config file itself, $XDG_CONFIG_HOME/deen/config.yaml.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// Load merges defaults <- config file <- environment variables into a
+// Config. A missing config file is not an error; every other setting
+// still resolves to its default.
+func Load() (*Config, error) {
+	c := &Config{}
+	for _, f := range fields() {
+		if err := setField(c, f.index, f.Default); err != nil {
+			return nil, fmt.Errorf("config: default for %q: %w", f.Key, err)
+		}
+	}
+
+	if path, err := Path(); err == nil {
+		if raw, err := readRaw(path); err == nil {
+			for _, f := range fields() {
+				if v, ok := raw[f.Key]; ok {
+					if err := setField(c, f.index, v); err != nil {
+						return nil, fmt.Errorf("config: %q in %s: %w", f.Key, path, err)
+					}
+				}
+			}
+		}
+	}
+
+	for _, f := range fields() {
+		if f.Env == "" {
+			continue
+		}
+		if v, ok := os.LookupEnv(f.Env); ok {
+			if err := setField(c, f.index, v); err != nil {
+				return nil, fmt.Errorf("config: env %s: %w", f.Env, err)
+			}
+		}
+	}
+	return c, nil
+}
+
+// readRaw loads the config file as a flat key->value map, same shape
+// Set writes.
+func readRaw(path string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw := map[string]string{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// setField parses value into the Config field at index according to
+// that field's Go type.
+func setField(c *Config, index int, value string) error {
+	fv := reflect.ValueOf(c).Elem().Field(index)
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Int:
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(n))
+	case reflect.Bool:
+		if value == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported config field kind %s", fv.Kind())
+	}
+	return nil
+}
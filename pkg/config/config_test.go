@@ -0,0 +1,112 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withConfigHome points XDG_CONFIG_HOME at a fresh temp dir for the
+// duration of the test and restores the previous value afterwards.
+func withConfigHome(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	old, had := os.LookupEnv("XDG_CONFIG_HOME")
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	t.Cleanup(func() {
+		if had {
+			os.Setenv("XDG_CONFIG_HOME", old)
+		} else {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		}
+	})
+	return dir
+}
+
+func TestLoadUsesDefaultsWithNoFileOrEnv(t *testing.T) {
+	withConfigHome(t)
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.GUITheme != "dark" {
+		t.Errorf("expected default gui.theme \"dark\", got %q", c.GUITheme)
+	}
+	if c.MaxPreviewBytes != 65536 {
+		t.Errorf("expected default gui.max_preview_bytes 65536, got %d", c.MaxPreviewBytes)
+	}
+}
+
+func TestLoadFileOverridesDefault(t *testing.T) {
+	withConfigHome(t)
+	writeConfigFile(t, "gui.theme: light\n")
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.GUITheme != "light" {
+		t.Errorf("expected file to override default, got %q", c.GUITheme)
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	withConfigHome(t)
+	writeConfigFile(t, "gui.theme: light\n")
+	os.Setenv("DEEN_GUI_THEME", "dark")
+	defer os.Unsetenv("DEEN_GUI_THEME")
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.GUITheme != "dark" {
+		t.Errorf("expected env to override file, got %q", c.GUITheme)
+	}
+}
+
+func TestSetRejectsUnknownKey(t *testing.T) {
+	withConfigHome(t)
+	if err := Set("not.a.real.key", "x"); err == nil {
+		t.Fatal("expected an error setting an unknown key")
+	}
+}
+
+func TestSetRejectsValueOfTheWrongKind(t *testing.T) {
+	withConfigHome(t)
+	if err := Set("gui.max_preview_bytes", "notanumber"); err == nil {
+		t.Fatal("expected an error setting a non-numeric value for an int key")
+	}
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load should still succeed, the bad value must not have been persisted: %v", err)
+	}
+}
+
+func TestSetThenLoadRoundTrips(t *testing.T) {
+	withConfigHome(t)
+	if err := Set("gui.theme", "light"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.GUITheme != "light" {
+		t.Errorf("expected Set to persist gui.theme, got %q", c.GUITheme)
+	}
+}
+
+func writeConfigFile(t *testing.T, contents string) {
+	t.Helper()
+	path, err := Path()
+	if err != nil {
+		t.Fatalf("Path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package hashs
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSHAKE128DefaultLength(t *testing.T) {
+	p := NewPluginSHAKE128()
+	out, err := p.ProcessStreamFunc(strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("ProcessStreamFunc: %v", err)
+	}
+	if len(out) != hex.EncodedLen(defaultShakeLen) {
+		t.Errorf("expected %d hex chars for the default length, got %d", hex.EncodedLen(defaultShakeLen), len(out))
+	}
+}
+
+func TestSHAKE256CustomLength(t *testing.T) {
+	p := NewPluginSHAKE256()
+	flags := p.AddCliOptionsFunc(&p, []string{"-len", "16"})
+	out, err := p.ProcessStreamWithCliFlagsFunc(flags, strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("ProcessStreamWithCliFlagsFunc: %v", err)
+	}
+	if len(out) != hex.EncodedLen(16) {
+		t.Errorf("expected %d hex chars for len=16, got %d", hex.EncodedLen(16), len(out))
+	}
+}
+
+func TestSHAKERejectsNonPositiveLength(t *testing.T) {
+	p := NewPluginSHAKE128()
+	flags := p.AddCliOptionsFunc(&p, []string{"-len", "-1"})
+	if _, err := p.ProcessStreamWithCliFlagsFunc(flags, strings.NewReader("hello")); err == nil {
+		t.Fatal("expected an error for a negative -len instead of a panic")
+	}
+}
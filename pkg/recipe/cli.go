@@ -0,0 +1,23 @@
+package recipe
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// RunFile does the work of a "deen recipe run <file>" subcommand: it
+// loads the recipe at path and streams stdin through its chain to
+// stdout. No CLI subcommand dispatch calls it; cmd/deen has no
+// "recipe" subcommand.
+func RunFile(path string, stdin io.Reader, stdout io.Writer) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("recipe: reading %q: %w", path, err)
+	}
+	r, err := Unmarshal(data)
+	if err != nil {
+		return fmt.Errorf("recipe: parsing %q: %w", path, err)
+	}
+	return Run(r, stdin, stdout)
+}
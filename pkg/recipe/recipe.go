@@ -0,0 +1,101 @@
+// Package recipe implements deen's persistent chain format: a
+// versioned document listing an ordered sequence of plugin steps
+// (plus their parsed CLI options) that can be replayed without a GUI.
+package recipe
+
+import (
+	"bytes"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/takeshixx/deen/internal/plugins"
+	"gopkg.in/yaml.v2"
+)
+
+// Version is the current recipe document version. Bump it whenever
+// the Recipe/Step shape changes in a way old documents can't satisfy.
+const Version = 1
+
+// Recipe is the serializable form of an encoder chain.
+type Recipe struct {
+	Version int    `yaml:"version" json:"version"`
+	Input   string `yaml:"input,omitempty" json:"input,omitempty"` // optional, base64-encoded
+	Steps   []Step `yaml:"steps" json:"steps"`
+}
+
+// Step is one plugin invocation in the chain.
+type Step struct {
+	Plugin    string            `yaml:"plugin" json:"plugin"`
+	Unprocess bool              `yaml:"unprocess,omitempty" json:"unprocess,omitempty"`
+	Options   map[string]string `yaml:"options,omitempty" json:"options,omitempty"`
+}
+
+// Marshal encodes a recipe as YAML.
+func Marshal(steps []Step, input []byte) ([]byte, error) {
+	r := &Recipe{
+		Version: Version,
+		Steps:   steps,
+	}
+	if len(input) > 0 {
+		r.Input = base64.StdEncoding.EncodeToString(input)
+	}
+	return yaml.Marshal(r)
+}
+
+// Unmarshal decodes a recipe document. gopkg.in/yaml.v2 also accepts
+// JSON, since JSON is a subset of YAML for the shapes we emit here.
+func Unmarshal(data []byte) (r *Recipe, err error) {
+	r = &Recipe{}
+	if err = yaml.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+	if r.Version < 1 || r.Version > Version {
+		return nil, fmt.Errorf("recipe: missing or unsupported version")
+	}
+	return r, nil
+}
+
+// SnapshotFlags captures a parsed flag.FlagSet's current values into a
+// plain map so they round-trip through a Step's Options.
+func SnapshotFlags(flags *flag.FlagSet) map[string]string {
+	if flags == nil {
+		return nil
+	}
+	values := make(map[string]string)
+	flags.VisitAll(func(f *flag.Flag) {
+		values[f.Name] = f.Value.String()
+	})
+	return values
+}
+
+// ApplyFlags re-parses previously snapshotted values back into a
+// freshly constructed flag.FlagSet.
+func ApplyFlags(flags *flag.FlagSet, values map[string]string) error {
+	for name, value := range values {
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("recipe: option %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Run streams in through every step of the recipe in order and writes
+// the final result to out.
+func Run(r *Recipe, in io.Reader, out io.Writer) error {
+	var reader io.Reader = in
+	for _, step := range r.Steps {
+		p, err := plugins.GetByName(step.Plugin)
+		if err != nil {
+			return fmt.Errorf("recipe: step %q: %w", step.Plugin, err)
+		}
+		processed, err := runStep(p, step, reader)
+		if err != nil {
+			return fmt.Errorf("recipe: step %q: %w", step.Plugin, err)
+		}
+		reader = bytes.NewReader(processed)
+	}
+	_, err := io.Copy(out, reader)
+	return err
+}
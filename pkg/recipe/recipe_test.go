@@ -0,0 +1,82 @@
+package recipe
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func mustFlagSet(t *testing.T, name, value string) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String(name, "", "")
+	if err := fs.Set(name, value); err != nil {
+		t.Fatalf("fs.Set: %v", err)
+	}
+	return fs
+}
+
+func TestMarshalUnmarshalRoundTrip(t *testing.T) {
+	steps := []Step{
+		{Plugin: "base64", Unprocess: false},
+		{Plugin: "shake128", Options: map[string]string{"len": "16"}},
+	}
+	input := []byte("hello world")
+
+	data, err := Marshal(steps, input)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	r, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if r.Version != Version {
+		t.Errorf("expected version %d, got %d", Version, r.Version)
+	}
+	if !reflect.DeepEqual(r.Steps, steps) {
+		t.Errorf("steps didn't round-trip: got %+v, want %+v", r.Steps, steps)
+	}
+	if r.Input == "" {
+		t.Fatal("expected embedded input to be set")
+	}
+}
+
+func TestMarshalUnmarshalEmptySteps(t *testing.T) {
+	data, err := Marshal(nil, nil)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	r, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(r.Steps) != 0 {
+		t.Errorf("expected zero steps, got %+v", r.Steps)
+	}
+	if r.Input != "" {
+		t.Errorf("expected no embedded input, got %q", r.Input)
+	}
+}
+
+func TestUnmarshalRejectsMissingVersion(t *testing.T) {
+	if _, err := Unmarshal([]byte("steps: []\n")); err == nil {
+		t.Fatal("expected an error for a document with no version")
+	}
+}
+
+func TestSnapshotAndApplyFlagsRoundTrip(t *testing.T) {
+	// A recipe step's Options must survive being snapshotted off one
+	// FlagSet and re-applied to a freshly constructed one, the way
+	// loading a recipe does.
+	original := mustFlagSet(t, "len", "32")
+	values := SnapshotFlags(original)
+
+	fresh := mustFlagSet(t, "len", "")
+	if err := ApplyFlags(fresh, values); err != nil {
+		t.Fatalf("ApplyFlags: %v", err)
+	}
+	if fresh.Lookup("len").Value.String() != "32" {
+		t.Errorf("expected len=32 after ApplyFlags, got %q", fresh.Lookup("len").Value.String())
+	}
+}
@@ -0,0 +1,24 @@
+package recipe
+
+import (
+	"io"
+
+	"github.com/takeshixx/deen/pkg/types"
+)
+
+// runStep executes a single recipe step against reader, applying its
+// snapshotted options (if any) through the plugin's own flag.FlagSet
+// so options round-trip exactly like they would on the CLI.
+func runStep(p *types.DeenPlugin, step Step, reader io.Reader) ([]byte, error) {
+	if len(step.Options) > 0 && p.AddCliOptionsFunc != nil && p.ProcessStreamWithCliFlagsFunc != nil && !step.Unprocess {
+		flags := p.AddCliOptionsFunc(p, nil)
+		if err := ApplyFlags(flags, step.Options); err != nil {
+			return nil, err
+		}
+		return p.ProcessStreamWithCliFlagsFunc(flags, reader)
+	}
+	if step.Unprocess {
+		return p.UnprocessStreamFunc(reader)
+	}
+	return p.ProcessStreamFunc(reader)
+}